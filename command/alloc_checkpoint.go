@@ -0,0 +1,131 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+)
+
+// AllocCheckpointCommand captures a running allocation's task into a CRIU
+// checkpoint via the task driver's Checkpoint RPC, so it can later be
+// restored with a `restore` block in the task's config.
+type AllocCheckpointCommand struct {
+	Ui cli.Ui
+}
+
+func (c *AllocCheckpointCommand) Help() string {
+	helpText := `
+Usage: nomad alloc checkpoint [options] <alloc-id> <task>
+
+  Checkpoint captures a running task into a CRIU checkpoint, so it can
+  later be resumed from that point with a "restore" block in the task's
+  driver config.
+
+General Options:
+
+  -address=<addr>
+    The address of the Nomad client to query. Defaults to
+    http://127.0.0.1:4646.
+
+Checkpoint Options:
+
+  -name=<name>
+    The name of the checkpoint to create. Defaults to the current
+    timestamp if omitted.
+
+  -dir=<dir>
+    The host directory to write the checkpoint into. Defaults to the task
+    driver's default checkpoint directory.
+
+  -leave-running
+    Leave the task's container running after the checkpoint is captured,
+    instead of stopping it.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocCheckpointCommand) Synopsis() string {
+	return "Checkpoint a running allocation's task"
+}
+
+func (c *AllocCheckpointCommand) Name() string { return "alloc checkpoint" }
+
+func (c *AllocCheckpointCommand) Run(args []string) int {
+	var address, name, dir string
+	var leaveRunning bool
+
+	flags := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&address, "address", "http://127.0.0.1:4646", "")
+	flags.StringVar(&name, "name", "", "")
+	flags.StringVar(&dir, "dir", "", "")
+	flags.BoolVar(&leaveRunning, "leave-running", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <alloc-id> <task>")
+		c.Ui.Error(c.Help())
+		return 1
+	}
+	allocID, task := args[0], args[1]
+
+	if name == "" {
+		name = time.Now().UTC().Format("20060102-150405")
+	}
+
+	if err := checkpointAlloc(address, allocID, task, name, dir, leaveRunning); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error checkpointing task %q: %s", task, err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Checkpointed task %q of allocation %q", task, allocID))
+	return 0
+}
+
+// checkpointRequest is the body sent to a client agent's per-task checkpoint
+// endpoint, mirroring the arguments Driver.Checkpoint takes on the plugin
+// boundary.
+type checkpointRequest struct {
+	Name         string `json:"Name"`
+	Dir          string `json:"Dir"`
+	LeaveRunning bool   `json:"LeaveRunning"`
+}
+
+// checkpointAlloc calls a client agent's checkpoint endpoint for a single
+// allocation's task, which dispatches to that task's driver Checkpoint RPC.
+func checkpointAlloc(address, allocID, task, name, dir string, leaveRunning bool) error {
+	body, err := json.Marshal(checkpointRequest{Name: name, Dir: dir, LeaveRunning: leaveRunning})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/client/allocation/%s/checkpoint/%s", address, allocID, task)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+var _ cli.Command = (*AllocCheckpointCommand)(nil)
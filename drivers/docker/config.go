@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// TaskConfig is the driver configuration of a task within a job.
+type TaskConfig struct {
+	Image       string         `codec:"image"`
+	Args        []string       `codec:"args"`
+	PidsLimit   int64          `codec:"pids_limit"`
+	PortMap     map[string]int `codec:"port_map"`
+	NetworkMode string         `codec:"network_mode"`
+	DNSServers  []string       `codec:"dns_servers"`
+	Logging     DockerLogging  `codec:"logging"`
+	Devices     []DockerDevice `codec:"devices"`
+	Mounts      []DockerMount  `codec:"mounts"`
+
+	// Volumes holds the `-v`-style shorthand bind mount strings
+	// ("<host-src>:<container-dst>[:options]"). Docker already parses
+	// SELinux relabel options ("z"/"Z") out of the trailing options
+	// segment itself, so no extra parsing is needed here — the string is
+	// passed straight through to the container's HostConfig.Binds.
+	Volumes []string `codec:"volumes"`
+
+	// Checkpoint names the CRIU checkpoint the running container was most
+	// recently captured into. It is set by the driver after a successful
+	// Checkpoint RPC, not by the job author.
+	Checkpoint DockerCheckpoint `codec:"checkpoint"`
+
+	// Restore starts the task from a previously captured CRIU checkpoint
+	// instead of pulling and running Image fresh.
+	Restore DockerRestore `codec:"restore"`
+
+	// ImagePull configures mirror references to pull, in order, before
+	// falling back to Image itself, with retry/backoff between pull
+	// attempts.
+	ImagePull DockerImagePull `codec:"image_pull"`
+}
+
+// DockerDevice represents a host device to be mapped into the container.
+type DockerDevice struct {
+	HostPath          string `codec:"host_path"`
+	ContainerPath     string `codec:"container_path"`
+	CgroupPermissions string `codec:"cgroup_permissions"`
+
+	// SelinuxLabel is an SELinux relabel suffix ("z" or "Z") applied to
+	// the host path's bind mount so the container can access it under
+	// enforcing SELinux without disabling the kernel's protections.
+	SelinuxLabel string `codec:"selinux_label"`
+}
+
+// DockerMount represents a host path mounted into the container.
+type DockerMount struct {
+	Target string `codec:"target"`
+	Source string `codec:"source"`
+
+	// SelinuxLabel is an SELinux relabel suffix ("z" or "Z"), see
+	// DockerDevice.SelinuxLabel.
+	SelinuxLabel string `codec:"selinux_label"`
+}
+
+// DockerLogging configures the container's log driver.
+type DockerLogging struct {
+	Type   string            `codec:"type"`
+	Config map[string]string `codec:"config"`
+}
+
+// DockerCheckpoint records where a CRIU checkpoint of the task's container
+// was last captured.
+type DockerCheckpoint struct {
+	Name string `codec:"name"`
+	Dir  string `codec:"dir"`
+}
+
+// DockerRestore starts a task from a previously captured CRIU checkpoint.
+type DockerRestore struct {
+	Name string `codec:"name"`
+	Dir  string `codec:"dir"`
+
+	// LeaveRunning mirrors Docker's `checkpoint create --leave-running`:
+	// whether the container kept running after the checkpoint was taken.
+	LeaveRunning bool `codec:"leave_running"`
+}
+
+// DockerImagePull configures one or more mirrors to try before falling back
+// to the task's Image, with retry/backoff between pull attempts. Each entry
+// in Mirrors is pulled as given, as a complete image reference.
+type DockerImagePull struct {
+	Mirrors  []string `codec:"mirrors"`
+	Insecure bool     `codec:"insecure"`
+	Retries  int      `codec:"retries"`
+	Backoff  string   `codec:"backoff"`
+}
+
+// Validate checks a TaskConfig for invalid combinations of fields that the
+// hclspec schema alone can't rule out.
+func (tc *TaskConfig) Validate() error {
+	return validateRestore(tc)
+}
+
+// taskConfigSpec is the hclspec schema for TaskConfig, shared by
+// (*Driver).TaskConfigSchema.
+var taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+	"image":        hclspec.NewAttr("image", "string", false),
+	"args":         hclspec.NewAttr("args", "list(string)", false),
+	"pids_limit":   hclspec.NewAttr("pids_limit", "number", false),
+	"port_map":     hclspec.NewBlockAttrs("port_map", "number", false),
+	"network_mode": hclspec.NewAttr("network_mode", "string", false),
+	"dns_servers":  hclspec.NewAttr("dns_servers", "list(string)", false),
+	"volumes":      hclspec.NewAttr("volumes", "list(string)", false),
+
+	"logging": hclspec.NewBlock("logging", false, hclspec.NewObject(map[string]*hclspec.Spec{
+		"type":   hclspec.NewAttr("type", "string", false),
+		"config": hclspec.NewBlockAttrs("config", "string", false),
+	})),
+
+	"devices": hclspec.NewBlockList("devices", hclspec.NewObject(map[string]*hclspec.Spec{
+		"host_path":          hclspec.NewAttr("host_path", "string", true),
+		"container_path":     hclspec.NewAttr("container_path", "string", false),
+		"cgroup_permissions": hclspec.NewAttr("cgroup_permissions", "string", false),
+		"selinux_label":      hclspec.NewAttr("selinux_label", "string", false),
+	})),
+
+	"mounts": hclspec.NewBlockList("mounts", hclspec.NewObject(map[string]*hclspec.Spec{
+		"target":        hclspec.NewAttr("target", "string", false),
+		"source":        hclspec.NewAttr("source", "string", false),
+		"selinux_label": hclspec.NewAttr("selinux_label", "string", false),
+	})),
+
+	"checkpoint": hclspec.NewBlock("checkpoint", false, hclspec.NewObject(map[string]*hclspec.Spec{
+		"name": hclspec.NewAttr("name", "string", false),
+		"dir":  hclspec.NewAttr("dir", "string", false),
+	})),
+
+	"restore": hclspec.NewBlock("restore", false, hclspec.NewObject(map[string]*hclspec.Spec{
+		"name":          hclspec.NewAttr("name", "string", false),
+		"dir":           hclspec.NewAttr("dir", "string", false),
+		"leave_running": hclspec.NewAttr("leave_running", "bool", false),
+	})),
+
+	"image_pull": hclspec.NewBlock("image_pull", false, hclspec.NewObject(map[string]*hclspec.Spec{
+		"mirrors":  hclspec.NewAttr("mirrors", "list(string)", false),
+		"insecure": hclspec.NewAttr("insecure", "bool", false),
+		"retries":  hclspec.NewAttr("retries", "number", false),
+		"backoff":  hclspec.NewAttr("backoff", "string", false),
+	})),
+})
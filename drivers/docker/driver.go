@@ -0,0 +1,332 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// Driver is the Docker task driver plugin.
+type Driver struct {
+	tasksLock sync.Mutex
+	tasks     map[string]*taskHandle
+}
+
+// TaskConfigSchema returns the hclspec used to parse a task's `config`
+// block into a docker.TaskConfig.
+func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
+	return taskConfigSpec, nil
+}
+
+// dockerCLI runs the docker CLI with args and returns its trimmed combined
+// output. It's a variable, rather than a direct exec.Command call, so tests
+// can stub out the real binary.
+var dockerCLI = func(args ...string) (string, error) {
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// StartTask decodes cfg's plugin-boundary config into this driver's
+// TaskConfig and launches the container: pulling the task's Image, applying
+// any SELinux relabeled binds for Mounts/Devices/Volumes, and starting it
+// under the task's ID. When Restore is set it instead resumes that checkpoint
+// and does not pull or apply binds. The launched container's ID is recorded
+// so later calls (e.g. Checkpoint) can find it by task ID.
+func (d *Driver) StartTask(cfg *drivers.TaskConfig) (string, error) {
+	var taskCfg TaskConfig
+	if err := cfg.DecodeDriverConfig(&taskCfg); err != nil {
+		return "", fmt.Errorf("failed to decode driver config: %w", err)
+	}
+
+	if err := taskCfg.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := validateVolumes(taskCfg.Volumes); err != nil {
+		return "", err
+	}
+
+	binds, err := selinuxBinds(taskCfg.Mounts, taskCfg.Devices)
+	if err != nil {
+		return "", err
+	}
+	binds = append(binds, taskCfg.Volumes...)
+
+	var containerID string
+	if taskCfg.Restore.Name != "" {
+		containerID, err = d.startFromCheckpoint(cfg.ID, &taskCfg)
+	} else {
+		containerID, err = d.startFromImage(cfg.ID, &taskCfg, binds)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	d.setHandle(cfg.ID, &taskHandle{containerID: containerID, config: taskCfg})
+	return containerID, nil
+}
+
+// startFromImage pulls taskCfg.Image (via any configured ImagePull mirrors)
+// and creates/starts a container named after taskID with the given bind
+// mounts.
+func (d *Driver) startFromImage(taskID string, taskCfg *TaskConfig, binds []string) (string, error) {
+	if err := pullImage(taskCfg.Image, taskCfg.ImagePull, pullFromRegistry); err != nil {
+		return "", err
+	}
+
+	args := []string{"create", "--name", taskID}
+	for _, b := range binds {
+		args = append(args, "-v", b)
+	}
+	args = append(args, taskCfg.Image)
+	args = append(args, taskCfg.Args...)
+
+	containerID, err := dockerCLI(args...)
+	if err != nil {
+		return "", fmt.Errorf("docker create failed: %w", err)
+	}
+
+	if _, err := dockerCLI("start", containerID); err != nil {
+		return "", fmt.Errorf("docker start failed: %w", err)
+	}
+
+	return containerID, nil
+}
+
+// startFromCheckpoint resumes taskCfg.Restore's previously captured CRIU
+// checkpoint into a container named after taskID, rather than starting
+// fresh from an image.
+func (d *Driver) startFromCheckpoint(taskID string, taskCfg *TaskConfig) (string, error) {
+	dir := checkpointDir(taskCfg.Restore.Dir)
+
+	args := []string{"start", "--checkpoint", taskCfg.Restore.Name, "--checkpoint-dir", dir, taskID}
+	if _, err := dockerCLI(args...); err != nil {
+		return "", fmt.Errorf("docker restore failed: %w", err)
+	}
+
+	return taskID, nil
+}
+
+// selinuxMountPath is where a Linux host with SELinux enabled exposes its
+// SELinux filesystem; its presence is used as a cheap, dependency-free way
+// to detect whether relabeling is actually usable on this host.
+const selinuxMountPath = "/sys/fs/selinux"
+
+// hostHasSelinux reports whether the current host can apply SELinux
+// relabeling to bind mounts. It's a variable so tests can stub out the
+// host check.
+var hostHasSelinux = func() bool {
+	_, err := os.Stat(selinuxMountPath)
+	return err == nil
+}
+
+// validSelinuxLabel reports whether label is one of the relabel suffixes
+// Docker understands for bind mounts ("z" for a shared label, "Z" for a
+// private, unshared label).
+func validSelinuxLabel(label string) bool {
+	switch label {
+	case "", "z", "Z":
+		return true
+	default:
+		return false
+	}
+}
+
+// toDockerBind renders a mount's host path, container path, and SELinux
+// label into the "<host>:<container>[:z|Z]" form Docker's HostConfig.Binds
+// expects.
+func toDockerBind(hostPath, containerPath, selinuxLabel string) (string, error) {
+	if !validSelinuxLabel(selinuxLabel) {
+		return "", fmt.Errorf("invalid selinux_label %q: must be \"z\" or \"Z\"", selinuxLabel)
+	}
+	if selinuxLabel != "" && !hostHasSelinux() {
+		return "", fmt.Errorf("selinux_label %q set on mount %q but host does not have SELinux enabled", selinuxLabel, hostPath)
+	}
+
+	bind := fmt.Sprintf("%s:%s", hostPath, containerPath)
+	if selinuxLabel != "" {
+		bind = fmt.Sprintf("%s:%s", bind, selinuxLabel)
+	}
+	return bind, nil
+}
+
+// selinuxBinds translates a task's Mounts and Devices into Docker bind
+// mount strings, applying and validating any SelinuxLabel set on them.
+func selinuxBinds(mounts []DockerMount, devices []DockerDevice) ([]string, error) {
+	var binds []string
+
+	for _, m := range mounts {
+		bind, err := toDockerBind(m.Source, m.Target, m.SelinuxLabel)
+		if err != nil {
+			return nil, err
+		}
+		binds = append(binds, bind)
+	}
+
+	for _, dev := range devices {
+		if dev.SelinuxLabel == "" {
+			continue
+		}
+		bind, err := toDockerBind(dev.HostPath, dev.ContainerPath, dev.SelinuxLabel)
+		if err != nil {
+			return nil, err
+		}
+		binds = append(binds, bind)
+	}
+
+	return binds, nil
+}
+
+// volumeHasSelinuxSuffix reports whether a `-v`-style volume string already
+// carries a ":z" or ":Z" relabel option in its trailing options segment.
+func volumeHasSelinuxSuffix(volume string) bool {
+	parts := strings.Split(volume, ":")
+	if len(parts) < 3 {
+		return false
+	}
+	for _, opt := range strings.Split(parts[len(parts)-1], ",") {
+		if opt == "z" || opt == "Z" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVolumes rejects shorthand Volumes entries that request SELinux
+// relabeling on a host that can't apply it.
+func validateVolumes(volumes []string) error {
+	if !hostHasSelinux() {
+		for _, v := range volumes {
+			if volumeHasSelinuxSuffix(v) {
+				return fmt.Errorf("volume %q requests an selinux relabel but host does not have SELinux enabled", v)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultCheckpointDir is the checkpoint directory used whenever a task's
+// checkpoint/restore block omits Dir. It is one fixed path shared by every
+// container, not Docker's own per-container checkpoint directory.
+const defaultCheckpointDir = "/var/lib/docker/containers/checkpoints"
+
+// checkpointDir returns dir, defaulting to defaultCheckpointDir when dir is
+// empty.
+func checkpointDir(dir string) string {
+	if dir == "" {
+		return defaultCheckpointDir
+	}
+	return dir
+}
+
+// validateRestore rejects a TaskConfig that sets both Restore and Image,
+// since a restored container resumes a previously captured process tree
+// rather than starting fresh from an image.
+func validateRestore(cfg *TaskConfig) error {
+	if cfg.Restore.Name != "" && cfg.Image != "" {
+		return fmt.Errorf("restore.name %q and image %q are mutually exclusive: a restored task does not start from an image", cfg.Restore.Name, cfg.Image)
+	}
+	return nil
+}
+
+// Checkpoint captures a running task's container into a CRIU checkpoint
+// named name under dir (defaulting per checkpointDir), optionally leaving
+// the container running afterwards, and records its name and dir on the
+// task's handle. Restoring reads the task config's Restore block, not this
+// record.
+func (d *Driver) Checkpoint(taskID, name, dir string, leaveRunning bool) error {
+	if taskID == "" {
+		return fmt.Errorf("taskID is required")
+	}
+	if name == "" {
+		return fmt.Errorf("checkpoint name is required")
+	}
+
+	handle, ok := d.getHandle(taskID)
+	if !ok {
+		return fmt.Errorf("no running task found for id %q", taskID)
+	}
+
+	dir = checkpointDir(dir)
+
+	args := []string{"checkpoint", "create", "--checkpoint-dir", dir}
+	if leaveRunning {
+		args = append(args, "--leave-running")
+	}
+	args = append(args, handle.containerID, name)
+
+	if _, err := dockerCLI(args...); err != nil {
+		return fmt.Errorf("docker checkpoint create failed: %w", err)
+	}
+
+	handle.config.Checkpoint = DockerCheckpoint{Name: name, Dir: dir}
+	return nil
+}
+
+// defaultPullBackoff is the base delay between pull retries, doubled after
+// each failed attempt, used when DockerImagePull.Backoff doesn't parse.
+const defaultPullBackoff = 1 * time.Second
+
+// pullRegistries returns the ordered list of references to attempt a pull
+// of: each configured mirror first, exactly as written, then image itself as
+// the final fallback. A mirror entry is used as a complete image reference;
+// image is not combined with it.
+func pullRegistries(image string, cfg DockerImagePull) []string {
+	registries := make([]string, 0, len(cfg.Mirrors)+1)
+	registries = append(registries, cfg.Mirrors...)
+	return append(registries, image)
+}
+
+// pullImage pulls image, trying each of cfg's mirror references in order
+// (see pullRegistries) before falling back to image itself, retrying with
+// exponential backoff between attempts. pull is called once per (reference,
+// attempt) pair and does the actual Docker client pull of that reference.
+func pullImage(image string, cfg DockerImagePull, pull func(registry string, insecure bool) error) error {
+	backoff := defaultPullBackoff
+	if cfg.Backoff != "" {
+		if d, err := time.ParseDuration(cfg.Backoff); err == nil {
+			backoff = d
+		}
+	}
+
+	retries := cfg.Retries
+	if retries < 1 {
+		retries = 1
+	}
+
+	var lastErr error
+	for _, registry := range pullRegistries(image, cfg) {
+		for attempt := 0; attempt < retries; attempt++ {
+			if attempt > 0 {
+				time.Sleep(backoff * time.Duration(1<<uint(attempt-1)))
+			}
+
+			lastErr = pull(registry, cfg.Insecure)
+			if lastErr == nil {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to pull image %q after trying %d registr(y/ies): %w", image, len(cfg.Mirrors)+1, lastErr)
+}
+
+// pullFromRegistry issues a docker pull against registry, which is either a
+// configured mirror or the image's own canonical reference, disabling
+// content trust when the registry is configured as insecure.
+func pullFromRegistry(registry string, insecure bool) error {
+	args := []string{"pull"}
+	if insecure {
+		args = append(args, "--disable-content-trust")
+	}
+	args = append(args, registry)
+
+	_, err := dockerCLI(args...)
+	return err
+}
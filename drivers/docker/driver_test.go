@@ -0,0 +1,315 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// encodedTaskConfig builds a drivers.TaskConfig whose driver config decodes
+// to the given docker.TaskConfig fields, going through the same
+// EncodeDriverConfig/DecodeDriverConfig path StartTask uses in production.
+func encodedTaskConfig(t *testing.T, id string, fields map[string]cty.Value) *drivers.TaskConfig {
+	t.Helper()
+
+	cfg := &drivers.TaskConfig{ID: id}
+	if err := cfg.EncodeDriverConfig(cty.ObjectVal(fields)); err != nil {
+		t.Fatalf("failed to encode driver config: %v", err)
+	}
+	return cfg
+}
+
+func TestStartTask_SelinuxBinds(t *testing.T) {
+	var gotArgs [][]string
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) {
+		gotArgs = append(gotArgs, append([]string{}, args...))
+		if args[0] == "create" {
+			return "abc123", nil
+		}
+		return "", nil
+	}
+	defer func() { dockerCLI = orig }()
+
+	origSelinux := hostHasSelinux
+	hostHasSelinux = func() bool { return true }
+	defer func() { hostHasSelinux = origSelinux }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"image": cty.StringVal("redis:3.2"),
+		"mounts": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"target":        cty.StringVal("/data"),
+				"source":        cty.StringVal("/opt/data"),
+				"selinux_label": cty.StringVal("Z"),
+			}),
+		}),
+	})
+
+	containerID, err := d.StartTask(cfg)
+	if err != nil {
+		t.Fatalf("StartTask() error = %v", err)
+	}
+	if containerID != "abc123" {
+		t.Fatalf("containerID = %q, want %q", containerID, "abc123")
+	}
+
+	var createArgs []string
+	for _, a := range gotArgs {
+		if a[0] == "create" {
+			createArgs = a
+		}
+	}
+	if createArgs == nil {
+		t.Fatal("expected a docker create invocation")
+	}
+
+	wantBind := "/opt/data:/data:Z"
+	if got := fmt.Sprint(createArgs); !contains(createArgs, wantBind) {
+		t.Fatalf("create args %v do not contain bind %q", got, wantBind)
+	}
+
+	if h, ok := d.getHandle("task1"); !ok || h.containerID != "abc123" {
+		t.Fatalf("expected handle for task1 with containerID abc123, got %+v (ok=%v)", h, ok)
+	}
+}
+
+func TestStartTask_RejectsSelinuxWithoutHostSupport(t *testing.T) {
+	if hostHasSelinux() {
+		t.Skip("this sandbox has SELinux enabled; rejection path can't be exercised")
+	}
+
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) { return "", nil }
+	defer func() { dockerCLI = orig }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"image": cty.StringVal("redis:3.2"),
+		"mounts": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"target":        cty.StringVal("/data"),
+				"source":        cty.StringVal("/opt/data"),
+				"selinux_label": cty.StringVal("Z"),
+			}),
+		}),
+	})
+
+	if _, err := d.StartTask(cfg); err == nil {
+		t.Fatal("expected an error starting a task with a selinux_label on a non-selinux host")
+	}
+}
+
+func TestStartTask_RejectsRestoreAndImage(t *testing.T) {
+	called := false
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) {
+		called = true
+		return "", nil
+	}
+	defer func() { dockerCLI = orig }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"image": cty.StringVal("redis:3.2"),
+		"restore": cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("pre-drain"),
+		}),
+	})
+
+	if _, err := d.StartTask(cfg); err == nil {
+		t.Fatal("expected an error starting a task with both image and restore set")
+	}
+	if called {
+		t.Fatal("dockerCLI should not be invoked when validation fails")
+	}
+}
+
+func TestStartTask_Restore(t *testing.T) {
+	var gotArgs []string
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) {
+		gotArgs = args
+		return "", nil
+	}
+	defer func() { dockerCLI = orig }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"restore": cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("pre-drain"),
+			"dir":  cty.StringVal("/custom/checkpoints"),
+		}),
+	})
+
+	containerID, err := d.StartTask(cfg)
+	if err != nil {
+		t.Fatalf("StartTask() error = %v", err)
+	}
+	if containerID != "task1" {
+		t.Fatalf("containerID = %q, want %q", containerID, "task1")
+	}
+
+	want := []string{"start", "--checkpoint", "pre-drain", "--checkpoint-dir", "/custom/checkpoints", "task1"}
+	if !equalArgs(gotArgs, want) {
+		t.Fatalf("dockerCLI args = %v, want %v", gotArgs, want)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	var gotArgs []string
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) {
+		if args[0] == "checkpoint" {
+			gotArgs = args
+		}
+		return "abc123", nil
+	}
+	defer func() { dockerCLI = orig }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"image": cty.StringVal("redis:3.2"),
+	})
+	if _, err := d.StartTask(cfg); err != nil {
+		t.Fatalf("StartTask() error = %v", err)
+	}
+
+	if err := d.Checkpoint("task1", "pre-drain", "", true); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+
+	want := []string{"checkpoint", "create", "--checkpoint-dir", defaultCheckpointDir, "--leave-running", "abc123", "pre-drain"}
+	if !equalArgs(gotArgs, want) {
+		t.Fatalf("dockerCLI checkpoint args = %v, want %v", gotArgs, want)
+	}
+
+	h, ok := d.getHandle("task1")
+	if !ok {
+		t.Fatal("expected handle for task1")
+	}
+	if h.config.Checkpoint.Name != "pre-drain" {
+		t.Fatalf("handle checkpoint name = %q, want %q", h.config.Checkpoint.Name, "pre-drain")
+	}
+}
+
+func TestStartTask_PullsFromMirrorBeforeCreating(t *testing.T) {
+	var pullArgs [][]string
+	orig := dockerCLI
+	dockerCLI = func(args ...string) (string, error) {
+		if args[0] == "pull" {
+			pullArgs = append(pullArgs, append([]string{}, args...))
+			if len(pullArgs) == 1 {
+				return "", fmt.Errorf("mirror unreachable")
+			}
+			return "", nil
+		}
+		if args[0] == "create" {
+			return "abc123", nil
+		}
+		return "", nil
+	}
+	defer func() { dockerCLI = orig }()
+
+	d := new(Driver)
+	cfg := encodedTaskConfig(t, "task1", map[string]cty.Value{
+		"image": cty.StringVal("redis:3.2"),
+		"image_pull": cty.ObjectVal(map[string]cty.Value{
+			"mirrors": cty.ListVal([]cty.Value{cty.StringVal("mirror.example/redis:3.2")}),
+			"retries": cty.NumberIntVal(1),
+		}),
+	})
+
+	if _, err := d.StartTask(cfg); err != nil {
+		t.Fatalf("StartTask() error = %v", err)
+	}
+
+	if len(pullArgs) != 2 {
+		t.Fatalf("expected 2 pull attempts (mirror then fallback), got %d: %v", len(pullArgs), pullArgs)
+	}
+	if !contains(pullArgs[0], "mirror.example/redis:3.2") {
+		t.Fatalf("first pull args %v did not target the mirror", pullArgs[0])
+	}
+	if !contains(pullArgs[1], "redis:3.2") {
+		t.Fatalf("second pull args %v did not fall back to the image", pullArgs[1])
+	}
+}
+
+func TestCheckpoint_UnknownTask(t *testing.T) {
+	d := new(Driver)
+	if err := d.Checkpoint("missing", "pre-drain", "", false); err == nil {
+		t.Fatal("expected an error checkpointing a task with no running handle")
+	}
+}
+
+func equalArgs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckpointDir(t *testing.T) {
+	cases := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{"explicit dir", "/custom/checkpoints", "/custom/checkpoints"},
+		{"empty dir defaults", "", defaultCheckpointDir},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := checkpointDir(c.dir); got != c.expected {
+				t.Fatalf("checkpointDir(%q) = %q, want %q", c.dir, got, c.expected)
+			}
+		})
+	}
+}
+
+func TestValidateRestore(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     *TaskConfig
+		wantErr bool
+	}{
+		{"neither set", &TaskConfig{}, false},
+		{"image only", &TaskConfig{Image: "redis:3.2"}, false},
+		{"restore only", &TaskConfig{Restore: DockerRestore{Name: "pre-drain"}}, false},
+		{
+			"restore and image both set",
+			&TaskConfig{Image: "redis:3.2", Restore: DockerRestore{Name: "pre-drain"}},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateRestore(c.cfg)
+			if c.wantErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
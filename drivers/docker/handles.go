@@ -0,0 +1,33 @@
+package docker
+
+import "sync"
+
+// taskHandle tracks the running container backing a single task, keyed by
+// task ID, so later calls like Checkpoint can find the container they
+// should act on.
+type taskHandle struct {
+	containerID string
+	config      TaskConfig
+}
+
+func (d *Driver) setHandle(taskID string, h *taskHandle) {
+	d.tasksLock.Lock()
+	defer d.tasksLock.Unlock()
+	if d.tasks == nil {
+		d.tasks = make(map[string]*taskHandle)
+	}
+	d.tasks[taskID] = h
+}
+
+func (d *Driver) getHandle(taskID string) (*taskHandle, bool) {
+	d.tasksLock.Lock()
+	defer d.tasksLock.Unlock()
+	h, ok := d.tasks[taskID]
+	return h, ok
+}
+
+func (d *Driver) removeHandle(taskID string) {
+	d.tasksLock.Lock()
+	defer d.tasksLock.Unlock()
+	delete(d.tasks, taskID)
+}
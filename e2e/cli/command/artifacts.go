@@ -0,0 +1,107 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// bundleArtifacts packages an environment's terraform working directory
+// (state, plan output, and any Nomad/Consul/Vault logs written there) along
+// with a `nomad operator debug` capture into a single gzipped tarball at
+// <reportDir>/<provider>-<name>.tar.gz, suitable for upload as CI build
+// artifacts. nomadPath is the binary fetched by fetchBinary for this run,
+// used to drive the debug capture so it doesn't depend on a `nomad` binary
+// being on PATH.
+func bundleArtifacts(meta Meta, flags *rootFlags, env *environment, nomadAddr, nomadPath string) (string, error) {
+	if flags.reportDir == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(flags.reportDir, 0755); err != nil {
+		return "", err
+	}
+
+	workDir := filepath.Join(flags.envPath, env.provider, env.name)
+	debugDir, err := os.MkdirTemp("", "nomad-e2e-debug-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(debugDir)
+
+	if nomadAddr != "" {
+		if err := captureOperatorDebug(meta, nomadPath, nomadAddr, debugDir); err != nil {
+			meta.logger.Warn("failed to capture nomad operator debug bundle", "error", err)
+		}
+	}
+
+	archivePath := filepath.Join(flags.reportDir, env.provider+"-"+env.name+".tar.gz")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	gz := gzip.NewWriter(archive)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for _, dir := range []string{workDir, debugDir} {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := addDirToTar(tw, dir); err != nil {
+			return "", err
+		}
+	}
+
+	return archivePath, nil
+}
+
+// captureOperatorDebug shells out to `nomad operator debug` so the capture
+// logic stays identical to what an operator would run by hand. It invokes
+// the nomadPath binary fetched for this run rather than searching PATH,
+// since the fetched binary is never installed onto PATH in CI.
+func captureOperatorDebug(meta Meta, nomadPath, nomadAddr, outDir string) error {
+	cmd := exec.Command(nomadPath, "operator", "debug", "-output="+outDir, "-address="+nomadAddr)
+	cmd.Env = os.Environ()
+	return cmd.Run()
+}
+
+func addDirToTar(tw *tar.Writer, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(filepath.Dir(dir), path)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
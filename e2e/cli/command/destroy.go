@@ -0,0 +1,40 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newDestroyCommand builds `destroy`, which tears down one or more
+// previously provisioned environments. It's the counterpart to `provision`
+// for CI jobs that provision, test, and destroy as separate steps.
+func newDestroyCommand(meta Meta, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "destroy <provider>/<name> [provider/name ...]",
+		Short:   "Tears down one or more previously provisioned e2e environments",
+		GroupID: groupManagement,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return destroyEnvironments(meta, flags, args)
+		},
+	}
+}
+
+func destroyEnvironments(meta Meta, flags *rootFlags, args []string) error {
+	environments, err := resolveEnvironments(meta, flags, args)
+	if err != nil {
+		return err
+	}
+
+	for _, env := range environments {
+		logger := meta.logger.With("name", env.name, "provider", env.provider)
+		logger.Debug("destroying environment")
+		if err := env.destroy(); err != nil {
+			logger.Error("failed to destroy environment", "error", err)
+			return err
+		}
+		meta.Ui.Output(fmt.Sprintf("%s/%s: destroyed", env.provider, env.name))
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolveEnvironments expands the <provider>/<name> glob arguments shared by
+// `run env`, `provision`, `destroy`, and `report` into concrete environments.
+func resolveEnvironments(meta Meta, flags *rootFlags, args []string) ([]*environment, error) {
+	environments := []*environment{}
+	for _, e := range args {
+		if len(strings.Split(e, "/")) != 2 {
+			meta.logger.Error("argument should be formated as <provider>/<environment>", "args", e)
+			return nil, fmt.Errorf("invalid environment argument %q, expected <provider>/<environment>", e)
+		}
+		envs, err := envsFromGlob(flags.envPath, e, flags.tfPath, meta.logger)
+		if err != nil {
+			meta.logger.Error("failed to build environment", "environment", e, "error", err)
+			return nil, err
+		}
+		environments = append(environments, envs...)
+	}
+	return environments, nil
+}
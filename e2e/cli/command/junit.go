@@ -0,0 +1,113 @@
+package command
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// junitTestsuite is the subset of the JUnit XML schema that CI systems
+// (Jenkins, GitHub Actions, CircleCI) key off of: a pass/fail count per
+// suite plus an optional failure message.
+type junitTestsuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitTestsuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestsuite `xml:"testsuite"`
+}
+
+// junitSuite renders a TestReport as a single JUnit testsuite named after the
+// environment it was run against.
+func junitSuite(name string, report *TestReport) junitTestsuite {
+	suite := junitTestsuite{
+		Name:  name,
+		Tests: 1,
+	}
+
+	tc := junitTestcase{Name: name}
+	if report.TotalFailedTests > 0 {
+		suite.Failures = 1
+		tc.Failure = &junitFailure{
+			Message: fmt.Sprintf("%d tests failed", report.TotalFailedTests),
+			Body:    report.Summary(),
+		}
+	}
+	suite.Cases = []junitTestcase{tc}
+
+	return suite
+}
+
+// junitErrorSuite renders a single failed testsuite for an environment that
+// never produced a TestReport, e.g. because provisioning or `go test`
+// itself failed to run, so the environment still shows up in JUnit output
+// instead of silently disappearing.
+func junitErrorSuite(name string, runErr error) junitTestsuite {
+	return junitTestsuite{
+		Name:     name,
+		Tests:    1,
+		Failures: 1,
+		Cases: []junitTestcase{{
+			Name: name,
+			Failure: &junitFailure{
+				Message: "environment failed before tests could run",
+				Body:    runErr.Error(),
+			},
+		}},
+	}
+}
+
+// writeJUnitFile marshals a single testsuite to <reportDir>/<name>-junit.xml.
+func writeJUnitFile(reportDir, name string, suite junitTestsuite) (string, error) {
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return "", err
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(reportDir, name+"-junit.xml")
+	if err := os.WriteFile(path, append([]byte(xml.Header), out...), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeCombinedJUnitFile wraps every environment's suite in a single
+// <testsuites> document at <reportDir>/junit.xml, the file most CI JUnit
+// collectors expect to find at the root of a report directory.
+func writeCombinedJUnitFile(reportDir string, suites []junitTestsuite) (string, error) {
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return "", err
+	}
+
+	doc := junitTestsuites{Suites: suites}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(reportDir, "junit.xml")
+	if err := os.WriteFile(path, append([]byte(xml.Header), out...), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
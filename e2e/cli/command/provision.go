@@ -0,0 +1,51 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newProvisionCommand builds `provision`, which stands up one or more
+// environments without running the test suite against them. It's useful for
+// warming an environment ahead of a `run env` invocation, or for CI jobs
+// that provision and test as separate steps.
+func newProvisionCommand(meta Meta, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "provision <provider>/<name> [provider/name ...]",
+		Short:   "Provisions one or more e2e environments without running tests",
+		GroupID: groupManagement,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return provisionEnvironments(meta, flags, args)
+		},
+	}
+}
+
+func provisionEnvironments(meta Meta, flags *rootFlags, args []string) error {
+	environments, err := resolveEnvironments(meta, flags, args)
+	if err != nil {
+		return err
+	}
+
+	nomadPath, err := fetchBinary(flags.nomadBinary)
+	defer os.RemoveAll(nomadPath)
+	if err != nil {
+		meta.logger.Error("failed to fetch nomad binary", "error", err)
+		return err
+	}
+
+	for _, env := range environments {
+		logger := meta.logger.With("name", env.name, "provider", env.provider)
+		logger.Debug("provisioning environment")
+		results, err := env.provision(nomadPath)
+		if err != nil {
+			logger.Error("failed to provision environment", "error", err)
+			return err
+		}
+		meta.Ui.Output(fmt.Sprintf("%s/%s: provisioned, nomad=%s consul=%s vault=%s",
+			env.provider, env.name, results.nomadAddr, results.consulAddr, results.vaultAddr))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newReportCommand builds `report`, which resolves the given
+// <provider>/<name> arguments the same way `run env` does and prints what
+// would be provisioned, without touching any infrastructure. It's meant for
+// sanity-checking environment globs in CI before kicking off a real `run` or
+// `provision`.
+func newReportCommand(meta Meta, flags *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "report <provider>/<name> [provider/name ...]",
+		Short:   "Prints the environments that would be targeted by run/provision",
+		GroupID: groupManagement,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			environments, err := resolveEnvironments(meta, flags, args)
+			if err != nil {
+				return err
+			}
+			for _, env := range environments {
+				meta.Ui.Output(fmt.Sprintf("%s/%s", env.provider, env.name))
+			}
+			return nil
+		},
+	}
+}
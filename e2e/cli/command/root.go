@@ -0,0 +1,120 @@
+package command
+
+import (
+	"fmt"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/spf13/cobra"
+)
+
+// Command groups mirror the grouping cobra renders in the root usage
+// template: commands that manage environments vs. commands that operate
+// against them.
+const (
+	groupManagement = "management"
+	groupOperation  = "operation"
+)
+
+// rootFlags holds the persistent flags shared by every subcommand. They are
+// bound once on the root command so `run`, `provision`, `destroy`, and
+// `report` all see the same environment/tf/binary configuration.
+type rootFlags struct {
+	envPath     string
+	tfPath      string
+	nomadBinary string
+	slow        bool
+	run         string
+	verbose     bool
+	parallel    int
+	reportDir   string
+}
+
+// NewRootCommand builds the nomad-e2e cobra root command. ui and logger are
+// threaded down to every subcommand via the Meta value passed to each
+// subcommand's constructor.
+func NewRootCommand(ui cli.Ui, logger hclog.Logger) *cobra.Command {
+	flags := &rootFlags{}
+
+	root := &cobra.Command{
+		Use:   "nomad-e2e",
+		Short: "Provision environments and run the Nomad e2e test suite",
+		// SilenceUsage only: usage text on every error (flag typos, Args
+		// validation, RunE failures) is noisy. Errors themselves must stay
+		// visible, so SilenceErrors is left at its default (false) and
+		// Execute's own "Error: ..." print is the single, consistent path
+		// for all of them, including flagErrorFunc's.
+		SilenceUsage: true,
+	}
+
+	root.SetUsageTemplate(usageTemplate)
+	root.SetHelpTemplate(helpTemplate)
+	root.SetFlagErrorFunc(flagErrorFunc)
+
+	root.AddGroup(
+		&cobra.Group{ID: groupManagement, Title: "Management Commands:"},
+		&cobra.Group{ID: groupOperation, Title: "Operation Commands:"},
+	)
+
+	pf := root.PersistentFlags()
+	pf.StringVar(&flags.envPath, "env-path", "./environments/", "Path to e2e environment terraform configs")
+	pf.StringVar(&flags.tfPath, "tf-path", "", "Path to a terraform binary to use for provisioning")
+	pf.StringVar(&flags.nomadBinary, "nomad-binary", "", "Path or URL of the nomad binary to test against")
+	pf.StringVar(&flags.run, "run", "", "Regex to target specific test suites/cases")
+	pf.BoolVar(&flags.slow, "slow", false, "Toggle slow running suites")
+	pf.BoolVarP(&flags.verbose, "verbose", "v", false, "Enable verbose/debug logging")
+	pf.IntVar(&flags.parallel, "parallel", 1, "Number of environments to provision and test concurrently")
+	pf.StringVar(&flags.reportDir, "report-dir", "", "Directory to write per-environment JUnit XML and artifact bundles to")
+
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if flags.verbose {
+			logger.SetLevel(hclog.Debug)
+		}
+	}
+
+	meta := Meta{Ui: ui, logger: logger}
+
+	root.AddCommand(
+		newRunCommand(meta, flags),
+		newProvisionCommand(meta, flags),
+		newDestroyCommand(meta, flags),
+		newReportCommand(meta, flags),
+	)
+
+	return root
+}
+
+// flagErrorFunc gives flag-parsing errors a stable, scriptable shape: a
+// pointer to --help appended to the error, rather than the full usage text.
+// It returns the error instead of exiting directly, so it goes through the
+// same Execute()/exit-code path as every other command failure.
+func flagErrorFunc(cmd *cobra.Command, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w\nSee '%s --help'.", err, cmd.CommandPath())
+}
+
+const usageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasAvailableSubCommands}}
+
+{{range .Commands}}{{if (eq .GroupID "management")}}{{rpad .Name .NamePadding }} {{.Short}}
+{{end}}{{end}}
+{{range .Commands}}{{if (eq .GroupID "operation")}}{{rpad .Name .NamePadding }} {{.Short}}
+{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespace}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespace}}{{end}}
+`
+
+const helpTemplate = `{{with (or .Long .Short)}}{{. | trimTrailingWhitespace}}
+
+{{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`
@@ -4,149 +4,278 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
+	"sync"
 
 	capi "github.com/hashicorp/consul/api"
 	hclog "github.com/hashicorp/go-hclog"
 	vapi "github.com/hashicorp/vault/api"
 	"github.com/mitchellh/cli"
+	"github.com/spf13/cobra"
 )
 
+// newRunCommand builds `run`, with `run local` and `run env <provider>/<name>
+// ...` as explicit subcommands. Running `nomad-e2e run <provider>/<name>`
+// directly (with no subcommand) keeps working exactly as it did before this
+// was ported to cobra, since cobra falls through to the parent's RunE when
+// no subcommand matches the first positional argument.
+func newRunCommand(meta Meta, flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "run [provider/environment ...]",
+		Short:   "Runs the e2e test suite",
+		GroupID: groupOperation,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgainstEnvironments(meta, flags, args)
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "local",
+		Short: "Runs the e2e test suite against a locally running Nomad cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLocal(meta, flags)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "env <provider>/<name> [provider/name ...]",
+		Short: "Provisions and runs the e2e test suite against one or more environments",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgainstEnvironments(meta, flags, args)
+		},
+	})
+
+	return cmd
+}
+
+// RunCommandFactory is kept as a compatibility shim for the mitchellh/cli
+// command map that historically dispatched `nomad-e2e run`. It drives the
+// `run` subcommand through the real root command, so the persistent flags
+// bound in NewRootCommand (-env-path, -nomad-binary, -parallel, etc.) are
+// registered and actually recognized, rather than being silently dropped.
 func RunCommandFactory(ui cli.Ui, logger hclog.Logger) cli.CommandFactory {
 	return func() (cli.Command, error) {
-		meta := Meta{
-			Ui:     ui,
-			logger: logger,
+		root := NewRootCommand(ui, logger)
+		runCmd, _, err := root.Find([]string{"run"})
+		if err != nil {
+			return nil, err
 		}
-		return &Run{Meta: meta}, nil
+		return &cobraShim{root: root, cmd: runCmd}, nil
 	}
 }
 
-type Run struct {
-	Meta
+// cobraShim adapts a cobra.Command to the cli.Command interface so it can
+// still be registered in a mitchellh/cli command map.
+type cobraShim struct {
+	root *cobra.Command
+	cmd  *cobra.Command
 }
 
-func (c *Run) Help() string {
-	helpText := `
-Usage: nomad-e2e run
-`
-	return strings.TrimSpace(helpText)
+func (s *cobraShim) Help() string {
+	return strings.TrimSpace(s.cmd.UsageString())
 }
 
-func (c *Run) Synopsis() string {
-	return "Runs the e2e test suite"
+func (s *cobraShim) Synopsis() string {
+	return s.cmd.Short
 }
 
-func (c *Run) Run(args []string) int {
-	var envPath string
-	var nomadBinary string
-	var tfPath string
-	var slow bool
-	var run string
-	cmdFlags := c.FlagSet("run")
-	cmdFlags.Usage = func() { c.Ui.Output(c.Help()) }
-	cmdFlags.StringVar(&envPath, "env-path", "./environments/", "Path to e2e environment terraform configs")
-	cmdFlags.StringVar(&nomadBinary, "nomad-binary", "", "")
-	cmdFlags.StringVar(&tfPath, "tf-path", "", "")
-	cmdFlags.StringVar(&run, "run", "", "Regex to target specific test suites/cases")
-	cmdFlags.BoolVar(&slow, "slow", false, "Toggle slow running suites")
-
-	if err := cmdFlags.Parse(args); err != nil {
-		c.logger.Error("failed to parse flags", "error", err)
-		return 1
-	}
-	if c.verbose {
-		c.logger.SetLevel(hclog.Debug)
-	}
-
-	args = cmdFlags.Args()
+// legacyLongFlag matches a single-dash, multi-character flag such as
+// "-nomad-binary=foo" or "-parallel" the way the pre-cobra stdlib `flag`
+// package accepted them. pflag, unlike stdlib flag, treats a leading single
+// dash as a (possibly bundled) shorthand flag, so these would otherwise
+// fail with "unknown shorthand flag" even though they're legal long-flag
+// syntax. "--" itself and already-double-dashed flags are left untouched.
+var legacyLongFlag = regexp.MustCompile(`^-([a-zA-Z][a-zA-Z0-9-]+)(=.*)?$`)
 
-	if len(args) == 0 {
-		c.logger.Info("no environments specified, running test suite locally")
-		var report *TestReport
-		var err error
-		if report, err = c.run(&runOpts{
-			slow:    slow,
-			verbose: c.verbose,
-		}); err != nil {
-			c.logger.Error("failed to run test suite", "error", err)
-			return 1
+// normalizeLegacyFlags rewrites pre-cobra single-dash long flags
+// ("-nomad-binary=foo") into the double-dash form pflag expects
+// ("--nomad-binary=foo"), so scripts written against the old stdlib-`flag`
+// based CLI keep working unmodified.
+func normalizeLegacyFlags(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "--" {
+			copy(out[i:], args[i:])
+			break
 		}
-		if report.TotalFailedTests == 0 {
-			c.Ui.Output("PASSED!")
-			if c.verbose {
-				c.Ui.Output(report.Summary())
-			}
+		if m := legacyLongFlag.FindStringSubmatch(a); m != nil {
+			out[i] = "-" + a
 		} else {
-			c.Ui.Output("***FAILED***")
-			c.Ui.Output(report.Summary())
+			out[i] = a
 		}
-		return 0
 	}
+	return out
+}
 
-	environments := []*environment{}
-	for _, e := range args {
-		if len(strings.Split(e, "/")) != 2 {
-			c.logger.Error("argument should be formated as <provider>/<environment>", "args", e)
-			return 1
-		}
-		envs, err := envsFromGlob(envPath, e, tfPath, c.logger)
-		if err != nil {
-			c.logger.Error("failed to build environment", "environment", e, "error", err)
-			return 1
+func (s *cobraShim) Run(args []string) int {
+	s.root.SetArgs(append([]string{"run"}, normalizeLegacyFlags(args)...))
+	if err := s.root.Execute(); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runLocal(meta Meta, flags *rootFlags) error {
+	meta.logger.Info("no environments specified, running test suite locally")
+	report, err := meta.run(&runOpts{
+		slow:    flags.slow,
+		verbose: flags.verbose,
+	})
+	if err != nil {
+		meta.logger.Error("failed to run test suite", "error", err)
+		return err
+	}
+
+	if report.TotalFailedTests == 0 {
+		meta.Ui.Output("PASSED!")
+		if flags.verbose {
+			meta.Ui.Output(report.Summary())
 		}
-		environments = append(environments, envs...)
+	} else {
+		meta.Ui.Output("***FAILED***")
+		meta.Ui.Output(report.Summary())
+		return fmt.Errorf("%d tests failed", report.TotalFailedTests)
+	}
+	return nil
+}
+
+// envResult captures the outcome of provisioning and testing a single
+// environment, so results can be aggregated after the worker pool drains
+// regardless of the order environments finish in.
+type envResult struct {
+	env    *environment
+	report *TestReport
+	suite  junitTestsuite
+	err    error
+}
+
+func runAgainstEnvironments(meta Meta, flags *rootFlags, args []string) error {
+	if len(args) == 0 {
+		return runLocal(meta, flags)
+	}
 
+	environments, err := resolveEnvironments(meta, flags, args)
+	if err != nil {
+		return err
 	}
 	envCount := len(environments)
+
 	// Use go-getter to fetch the nomad binary
-	nomadPath, err := fetchBinary(nomadBinary)
+	nomadPath, err := fetchBinary(flags.nomadBinary)
 	defer os.RemoveAll(nomadPath)
 	if err != nil {
-		c.logger.Error("failed to fetch nomad binary", "error", err)
-		return 1
+		meta.logger.Error("failed to fetch nomad binary", "error", err)
+		return err
+	}
+
+	parallel := flags.parallel
+	if parallel < 1 {
+		parallel = 1
 	}
+	meta.logger.Debug("starting tests", "totalEnvironments", envCount, "parallel", parallel)
 
-	c.logger.Debug("starting tests", "totalEnvironments", envCount)
+	results := make([]envResult, envCount)
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
 	for i, env := range environments {
-		logger := c.logger.With("name", env.name, "provider", env.provider)
-		logger.Debug("provisioning environment")
-		results, err := env.provision(nomadPath)
-		if err != nil {
-			logger.Error("failed to provision environment", "error", err)
-			return 1
+		i, env := i, env
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = testEnvironment(meta, flags, env, nomadPath)
+		}()
+	}
+	wg.Wait()
+
+	var suites []junitTestsuite
+	failed := false
+	for i, res := range results {
+		env := res.env
+		switch {
+		case res.err != nil:
+			failed = true
+			meta.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: ***FAILED*** %s\n", i+1, envCount, env.provider, env.name, res.err))
+		case res.report.TotalFailedTests == 0:
+			meta.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: PASSED!\n", i+1, envCount, env.provider, env.name))
+			if flags.verbose {
+				meta.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: %s", i+1, envCount, env.provider, env.name, res.report.Summary()))
+			}
+		default:
+			failed = true
+			meta.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: ***FAILED***\n", i+1, envCount, env.provider, env.name))
+			meta.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: %s", i+1, envCount, env.provider, env.name, res.report.Summary()))
 		}
+		suites = append(suites, res.suite)
+	}
 
-		opts := &runOpts{
-			provider:   env.provider,
-			env:        env.name,
-			slow:       slow,
-			verbose:    c.verbose,
-			nomadAddr:  results.nomadAddr,
-			consulAddr: results.consulAddr,
-			vaultAddr:  results.vaultAddr,
+	if flags.reportDir != "" && len(suites) > 0 {
+		if _, err := writeCombinedJUnitFile(flags.reportDir, suites); err != nil {
+			meta.logger.Warn("failed to write combined junit report", "error", err)
 		}
+	}
+
+	if failed {
+		return fmt.Errorf("one or more environments failed")
+	}
+	return nil
+}
+
+// testEnvironment provisions, tests, and tears down a single environment. It
+// always runs teardown, even when provisioning only partially succeeds or
+// the test run itself fails, so a -parallel batch never leaves
+// infrastructure behind.
+func testEnvironment(meta Meta, flags *rootFlags, env *environment, nomadPath string) envResult {
+	logger := meta.logger.With("name", env.name, "provider", env.provider)
+	logger.Debug("provisioning environment")
+
+	suiteName := env.provider + "-" + env.name
 
-		var report *TestReport
-		if report, err = c.run(opts); err != nil {
-			logger.Error("failed to run tests against environment", "error", err)
-			return 1
+	defer func() {
+		if err := env.destroy(); err != nil {
+			logger.Error("failed to tear down environment", "error", err)
 		}
-		if report.TotalFailedTests == 0 {
+	}()
 
-			c.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: PASSED!\n", i+1, envCount, env.provider, env.name))
-			if c.verbose {
-				c.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: %s", i+1, envCount, env.provider, env.name, report.Summary()))
-			}
-		} else {
-			c.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: ***FAILED***\n", i+1, envCount, env.provider, env.name))
-			c.Ui.Output(fmt.Sprintf("[%d/%d] %s/%s: %s", i+1, envCount, env.provider, env.name, report.Summary()))
+	results, err := env.provision(nomadPath)
+	if err != nil {
+		logger.Error("failed to provision environment", "error", err)
+		return envResult{env: env, err: err, suite: junitErrorSuite(suiteName, err)}
+	}
+
+	opts := &runOpts{
+		provider:   env.provider,
+		env:        env.name,
+		slow:       flags.slow,
+		verbose:    flags.verbose,
+		nomadAddr:  results.nomadAddr,
+		consulAddr: results.consulAddr,
+		vaultAddr:  results.vaultAddr,
+	}
+
+	report, err := meta.run(opts)
+	if err != nil {
+		logger.Error("failed to run tests against environment", "error", err)
+		return envResult{env: env, err: err, suite: junitErrorSuite(suiteName, err)}
+	}
+
+	suite := junitSuite(suiteName, report)
+	if flags.reportDir != "" {
+		if _, err := writeJUnitFile(flags.reportDir, suiteName, suite); err != nil {
+			logger.Warn("failed to write junit report", "error", err)
+		}
+		if _, err := bundleArtifacts(meta, flags, env, results.nomadAddr, nomadPath); err != nil {
+			logger.Warn("failed to bundle artifacts", "error", err)
 		}
 	}
-	return 0
+
+	return envResult{env: env, report: report, suite: suite}
 }
 
-func (c *Run) run(opts *runOpts) (*TestReport, error) {
+func (meta Meta) run(opts *runOpts) (*TestReport, error) {
 	goBin, err := exec.LookPath("go")
 	if err != nil {
 		return nil, err
@@ -166,13 +295,12 @@ func (c *Run) run(opts *runOpts) (*TestReport, error) {
 	}
 
 	dec := NewDecoder(out)
-	report, err := dec.Decode(c.logger.Named("run.gotest"))
+	report, err := dec.Decode(meta.logger.Named("run.gotest"))
 	if err != nil {
 		return nil, err
 	}
 
 	return report, nil
-
 }
 
 type runOpts struct {
@@ -218,4 +346,4 @@ func (opts *runOpts) goEnv() []string {
 	}
 
 	return env
-}
\ No newline at end of file
+}
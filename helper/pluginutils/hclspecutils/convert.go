@@ -0,0 +1,103 @@
+// Package hclspecutils converts the driver-agnostic hclspec.Spec schema
+// into the hcldec.Spec the hcl2 decoder actually understands.
+package hclspecutils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcldec"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Convert turns a driver's hclspec.Spec tree into an hcldec.Spec that
+// hcldec.Decode can use directly.
+func Convert(spec *hclspec.Spec) (hcldec.Spec, hcl.Diagnostics) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	switch spec.Kind {
+	case hclspec.KindObject:
+		obj := make(hcldec.ObjectSpec, len(spec.Object))
+		var diags hcl.Diagnostics
+		for key, child := range spec.Object {
+			childSpec, childDiags := convertChild(child)
+			diags = append(diags, childDiags...)
+			obj[key] = childSpec
+		}
+		return obj, diags
+	default:
+		return convertChild(spec)
+	}
+}
+
+func convertChild(spec *hclspec.Spec) (hcldec.Spec, hcl.Diagnostics) {
+	switch spec.Kind {
+	case hclspec.KindAttr:
+		ty, diags := parseType(spec.Type)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return &hcldec.AttrSpec{Name: spec.Name, Type: ty, Required: spec.Required}, diags
+
+	case hclspec.KindBlock:
+		nested, diags := Convert(spec.Nested)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return &hcldec.BlockSpec{TypeName: spec.Name, Nested: nested, Required: spec.Required}, diags
+
+	case hclspec.KindBlockList:
+		nested, diags := Convert(spec.Nested)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return &hcldec.BlockListSpec{TypeName: spec.Name, Nested: nested}, diags
+
+	case hclspec.KindBlockAttrs:
+		ty, diags := parseType(spec.Type)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return &hcldec.BlockAttrsSpec{TypeName: spec.Name, ElementType: ty, Required: spec.Required}, diags
+
+	default:
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid hclspec node",
+			Detail:   fmt.Sprintf("unknown spec kind %v for %q", spec.Kind, spec.Name),
+		}}
+	}
+}
+
+// parseType supports the small subset of cty type expressions nomad's
+// drivers actually use: string, number, bool, and list(<primitive>).
+func parseType(expr string) (cty.Type, hcl.Diagnostics) {
+	expr = strings.TrimSpace(expr)
+
+	switch expr {
+	case "string":
+		return cty.String, nil
+	case "number":
+		return cty.Number, nil
+	case "bool":
+		return cty.Bool, nil
+	}
+
+	if strings.HasPrefix(expr, "list(") && strings.HasSuffix(expr, ")") {
+		elem, diags := parseType(expr[len("list(") : len(expr)-1])
+		if diags.HasErrors() {
+			return cty.NilType, diags
+		}
+		return cty.List(elem), nil
+	}
+
+	return cty.NilType, hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid type expression",
+		Detail:   fmt.Sprintf("unsupported hclspec type expression %q", expr),
+	}}
+}
@@ -0,0 +1,120 @@
+// Package hclutils decodes a task's driver config block, written as either
+// native HCL or JSON, against a driver's hcldec.Spec.
+package hclutils
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/hcl"
+	hcl2 "github.com/hashicorp/hcl2/hcl"
+	hcl2json "github.com/hashicorp/hcl2/hcl/json"
+	"github.com/hashicorp/hcl2/hcldec"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclspecutils"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testingT is the subset of *testing.T these helpers need, so they can be
+// called from any package's tests without importing "testing" themselves.
+type testingT interface {
+	Fatalf(format string, args ...interface{})
+	Helper()
+}
+
+// ParseHclInterface decodes a generic, JSON-shaped config value (as
+// produced by HclConfigToInterface or JsonConfigToInterface) against spec,
+// evaluating any "${...}" expressions against vars.
+func ParseHclInterface(val interface{}, spec hcldec.Spec, vars map[string]cty.Value) (cty.Value, hcl2.Diagnostics) {
+	jsonBytes, err := json.Marshal(val)
+	if err != nil {
+		return cty.NilVal, hcl2.Diagnostics{&hcl2.Diagnostic{
+			Severity: hcl2.DiagError,
+			Summary:  "Failed to marshal config",
+			Detail:   err.Error(),
+		}}
+	}
+
+	file, diags := hcl2json.Parse(jsonBytes, "<config>")
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	evalCtx := &hcl2.EvalContext{Variables: vars}
+	value, decDiags := hcldec.Decode(file.Body, spec, evalCtx)
+	diags = append(diags, decDiags...)
+	return value, diags
+}
+
+// HclConfigToInterface parses a `config { ... }` fixture written in native
+// HCL syntax and returns the generic map inside the wrapping "config"
+// block, ready to hand to ParseHclInterface.
+func HclConfigToInterface(t testingT, configStr string) interface{} {
+	t.Helper()
+
+	root, err := hcl.Parse(configStr)
+	if err != nil {
+		t.Fatalf("failed to hcl parse config: %v", err)
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, root); err != nil {
+		t.Fatalf("failed to decode hcl config: %v", err)
+	}
+
+	cfg := m["config"]
+	if list, ok := cfg.([]map[string]interface{}); ok && len(list) > 0 {
+		return list[0]
+	}
+	return cfg
+}
+
+// JsonConfigToInterface parses a `{"Config": {...}}` fixture written as
+// JSON and returns the generic map inside the wrapping "Config" key, ready
+// to hand to ParseHclInterface.
+func JsonConfigToInterface(t testingT, configStr string) interface{} {
+	t.Helper()
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(configStr), &m); err != nil {
+		t.Fatalf("failed to json parse config: %v", err)
+	}
+
+	return m["Config"]
+}
+
+// ConfigParser decodes repeated JSON fixtures against a single hclspec,
+// used by tests that check null/omitted-field behavior across many cases.
+type ConfigParser struct {
+	spec hcldec.Spec
+}
+
+// NewConfigParser builds a ConfigParser from a raw hclspec.Spec tree,
+// converting it to an hcldec.Spec once up front.
+func NewConfigParser(spec *hclspec.Spec) *ConfigParser {
+	decSpec, diags := hclspecutils.Convert(spec)
+	if diags.HasErrors() {
+		panic(diags.Error())
+	}
+	return &ConfigParser{spec: decSpec}
+}
+
+// ParseJson decodes the given `{"Config": {...}}` JSON fixture against the
+// parser's spec and stores the result in out, which must be a pointer.
+func (p *ConfigParser) ParseJson(t testingT, configStr string, out interface{}) {
+	t.Helper()
+
+	val := JsonConfigToInterface(t, configStr)
+	ctyValue, diags := ParseHclInterface(val, p.spec, nil)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse config: %s", diags.Error())
+	}
+
+	tc := &drivers.TaskConfig{}
+	if err := tc.EncodeDriverConfig(ctyValue); err != nil {
+		t.Fatalf("failed to encode config: %v", err)
+	}
+	if err := tc.DecodeDriverConfig(out); err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+}
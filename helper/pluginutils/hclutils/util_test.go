@@ -284,6 +284,68 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 			},
 			expectedType: &docker.TaskConfig{},
 		},
+		{
+			name: "volumes selinux",
+			config: hclutils.HclConfigToInterface(t, `
+						config {
+							image = "redis:3.2"
+							volumes = [
+								"/opt/data:/data:Z",
+								"/opt/logs:/logs:ro,z"
+							]
+						}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Image: "redis:3.2",
+				Volumes: []string{
+					"/opt/data:/data:Z",
+					"/opt/logs:/logs:ro,z",
+				},
+				Devices: []docker.DockerDevice{},
+				Mounts:  []docker.DockerMount{},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
+		{
+			name: "mounts and devices selinux labels",
+			config: hclutils.HclConfigToInterface(t, `
+						config {
+							image = "redis:3.2"
+							mounts = [
+								{
+									target         = "/data"
+									source         = "/opt/data"
+									selinux_label = "Z"
+								}
+							]
+							devices = [
+								{
+									host_path          = "/dev/sda1"
+									container_path     = "/dev/xvdc"
+									selinux_label      = "z"
+								}
+							]
+						}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Image: "redis:3.2",
+				Mounts: []docker.DockerMount{
+					{
+						Target:       "/data",
+						Source:       "/opt/data",
+						SelinuxLabel: "Z",
+					},
+				},
+				Devices: []docker.DockerDevice{
+					{
+						HostPath:      "/dev/sda1",
+						ContainerPath: "/dev/xvdc",
+						SelinuxLabel:  "z",
+					},
+				},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
 		{
 			name: "docker_logging",
 			config: hclutils.HclConfigToInterface(t, `
@@ -314,6 +376,49 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 			},
 			expectedType: &docker.TaskConfig{},
 		},
+		{
+			name: "restore",
+			config: hclutils.HclConfigToInterface(t, `
+						config {
+							restore {
+								name          = "pre-drain"
+								dir           = "/var/lib/docker/checkpoints"
+								leave_running = true
+							}
+						}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Restore: docker.DockerRestore{
+					Name:         "pre-drain",
+					Dir:          "/var/lib/docker/checkpoints",
+					LeaveRunning: true,
+				},
+				Devices: []docker.DockerDevice{},
+				Mounts:  []docker.DockerMount{},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
+		{
+			name: "checkpoint json",
+			config: hclutils.JsonConfigToInterface(t, `
+					{
+						"Config": {
+							"checkpoint": [{
+								"name": "pre-drain",
+								"dir": ""
+							}]
+				}
+					}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Checkpoint: docker.DockerCheckpoint{
+					Name: "pre-drain",
+				},
+				Devices: []docker.DockerDevice{},
+				Mounts:  []docker.DockerMount{},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
 		{
 			name: "docker_json",
 			config: hclutils.JsonConfigToInterface(t, `
@@ -351,6 +456,59 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 			},
 			expectedType: &docker.TaskConfig{},
 		},
+		{
+			name: "image_pull",
+			config: hclutils.HclConfigToInterface(t, `
+						config {
+							image = "redis:3.2"
+							image_pull {
+								mirrors  = ["https://mirror.example/v2/"]
+								insecure = false
+								retries  = 3
+								backoff  = "5s"
+							}
+						}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Image: "redis:3.2",
+				ImagePull: docker.DockerImagePull{
+					Mirrors: []string{"https://mirror.example/v2/"},
+					Retries: 3,
+					Backoff: "5s",
+				},
+				Devices: []docker.DockerDevice{},
+				Mounts:  []docker.DockerMount{},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
+		{
+			name: "image_pull json",
+			config: hclutils.JsonConfigToInterface(t, `
+					{
+						"Config": {
+							"image": "redis:3.2",
+							"image_pull": [{
+								"mirrors": ["https://mirror.example/v2/"],
+								"insecure": true,
+								"retries": 5,
+								"backoff": "10s"
+							}]
+				}
+					}`),
+			spec: dockerDecSpec,
+			expected: &docker.TaskConfig{
+				Image: "redis:3.2",
+				ImagePull: docker.DockerImagePull{
+					Mirrors:  []string{"https://mirror.example/v2/"},
+					Insecure: true,
+					Retries:  5,
+					Backoff:  "10s",
+				},
+				Devices: []docker.DockerDevice{},
+				Mounts:  []docker.DockerMount{},
+			},
+			expectedType: &docker.TaskConfig{},
+		},
 	}
 
 	for _, c := range cases {
@@ -379,6 +537,38 @@ func TestParseHclInterface_Hcl(t *testing.T) {
 	}
 }
 
+// TestParseHclInterface_RestoreAndImageConflict checks that a config setting
+// both image and restore parses and decodes cleanly (the hclspec schema has
+// no way to express the conflict), but fails the driver's own Validate once
+// decoded, mirroring the check StartTask performs before launching a
+// container.
+func TestParseHclInterface_RestoreAndImageConflict(t *testing.T) {
+	dockerDriver := new(docker.Driver)
+	dockerSpec, err := dockerDriver.TaskConfigSchema()
+	require.NoError(t, err)
+	dockerDecSpec, diags := hclspecutils.Convert(dockerSpec)
+	require.False(t, diags.HasErrors())
+
+	config := hclutils.HclConfigToInterface(t, `
+				config {
+					image = "redis:3.2"
+					restore {
+						name = "pre-drain"
+					}
+				}`)
+
+	ctyValue, diag := hclutils.ParseHclInterface(config, dockerDecSpec, nil)
+	require.False(t, diag.HasErrors())
+
+	taskConfig := &drivers.TaskConfig{}
+	require.NoError(t, taskConfig.EncodeDriverConfig(ctyValue))
+
+	var tc docker.TaskConfig
+	require.NoError(t, taskConfig.DecodeDriverConfig(&tc))
+
+	require.Error(t, tc.Validate())
+}
+
 func TestParseNullFields(t *testing.T) {
 	spec := hclspec.NewObject(map[string]*hclspec.Spec{
 		"array_field":   hclspec.NewAttr("array_field", "list(string)", false),
@@ -458,3 +648,26 @@ func TestParseNullFields(t *testing.T) {
 		})
 	}
 }
+
+// TestParseNullFields_ImagePull checks that an omitted image_pull block
+// decodes to a zero-value DockerImagePull rather than erroring, mirroring
+// TestParseNullFields for the docker-specific image_pull block.
+func TestParseNullFields_ImagePull(t *testing.T) {
+	dockerDriver := new(docker.Driver)
+	dockerSpec, err := dockerDriver.TaskConfigSchema()
+	require.NoError(t, err)
+	dockerDecSpec, diags := hclspecutils.Convert(dockerSpec)
+	require.False(t, diags.HasErrors())
+
+	val := hclutils.JsonConfigToInterface(t, `{"Config": {"image": "redis:3.2"}}`)
+	ctyValue, diags := hclutils.ParseHclInterface(val, dockerDecSpec, nil)
+	require.False(t, diags.HasErrors())
+
+	taskConfig := &drivers.TaskConfig{}
+	require.NoError(t, taskConfig.EncodeDriverConfig(ctyValue))
+
+	var tc docker.TaskConfig
+	require.NoError(t, taskConfig.DecodeDriverConfig(&tc))
+
+	require.Equal(t, docker.DockerImagePull{}, tc.ImagePull)
+}
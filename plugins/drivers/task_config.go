@@ -0,0 +1,161 @@
+// Package drivers contains the types shared across the task driver plugin
+// boundary.
+package drivers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// TaskConfig is the task configuration passed across the driver plugin
+// boundary. Drivers decode the opaque, plugin-specific portion of it
+// (rawDriverConfig) into their own concrete config struct via
+// DecodeDriverConfig.
+type TaskConfig struct {
+	ID   string
+	Name string
+
+	rawDriverConfig []byte
+}
+
+// EncodeDriverConfig stores a cty.Value produced by decoding a task's HCL
+// config block against a driver's hclspec, so it can cross the plugin
+// boundary and later be decoded into the driver's own config struct.
+func (tc *TaskConfig) EncodeDriverConfig(val cty.Value) error {
+	data, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return err
+	}
+	tc.rawDriverConfig = data
+	return nil
+}
+
+// DecodeDriverConfig decodes the previously encoded driver config into t,
+// which must be a pointer to the driver's config struct. Fields are
+// matched by their `codec` struct tag, falling back to the lowercased
+// field name, mirroring the names drivers already give their hclspec
+// attributes/blocks.
+func (tc *TaskConfig) DecodeDriverConfig(t interface{}) error {
+	if len(tc.rawDriverConfig) == 0 {
+		return nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(tc.rawDriverConfig, &raw); err != nil {
+		return err
+	}
+
+	return decodeCodec(raw, t)
+}
+
+// decodeCodec copies the fields of raw into target, a pointer to a struct,
+// matching keys against each field's `codec` tag.
+func decodeCodec(raw map[string]interface{}, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decodeCodec: target must be a pointer to a struct, got %T", target)
+	}
+
+	structVal := v.Elem()
+	structType := structVal.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("codec")
+		if tag == "" {
+			tag = strings.ToLower(field.Name)
+		}
+
+		val, ok := raw[tag]
+		if !ok || val == nil {
+			continue
+		}
+
+		if err := setField(structVal.Field(i), val); err != nil {
+			return fmt.Errorf("field %q: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func setField(field reflect.Value, raw interface{}) error {
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", raw)
+		}
+		field.SetString(s)
+
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", raw)
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := raw.(float64)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", raw)
+		}
+		field.SetInt(int64(n))
+
+	case reflect.Map:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object, got %T", raw)
+		}
+		newMap := reflect.MakeMapWithSize(field.Type(), len(m))
+		for k, val := range m {
+			ev := reflect.New(field.Type().Elem()).Elem()
+			if err := setField(ev, val); err != nil {
+				return err
+			}
+			newMap.SetMapIndex(reflect.ValueOf(k), ev)
+		}
+		field.Set(newMap)
+
+	case reflect.Slice:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array, got %T", raw)
+		}
+		elemType := field.Type().Elem()
+		newSlice := reflect.MakeSlice(field.Type(), 0, len(list))
+		for _, item := range list {
+			ev := reflect.New(elemType).Elem()
+			if elemType.Kind() == reflect.Struct {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("expected object in array, got %T", item)
+				}
+				if err := decodeCodec(m, ev.Addr().Interface()); err != nil {
+					return err
+				}
+			} else if err := setField(ev, item); err != nil {
+				return err
+			}
+			newSlice = reflect.Append(newSlice, ev)
+		}
+		field.Set(newSlice)
+
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object, got %T", raw)
+		}
+		return decodeCodec(m, field.Addr().Interface())
+
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package hclspec provides a small, serializable description of the shape
+// a driver's TaskConfig HCL/JSON block should take. A *Spec tree is built up
+// with the New* constructors below and then turned into a decodable
+// hcldec.Spec by helper/pluginutils/hclspecutils.Convert.
+package hclspec
+
+// Kind identifies which decoding behavior a Spec node uses.
+type Kind int
+
+const (
+	KindObject Kind = iota
+	KindAttr
+	KindBlock
+	KindBlockList
+	KindBlockAttrs
+)
+
+// Spec is a node in the schema tree for a driver's TaskConfig block. Only
+// the fields relevant to Kind are populated; the rest are left zero.
+type Spec struct {
+	Kind Kind
+
+	// Name is the HCL attribute or block type name this node decodes,
+	// used for everything except the root KindObject node.
+	Name string
+
+	// Type is a cty type expression, e.g. "string", "number", "bool", or
+	// "list(string)". Only set for KindAttr and KindBlockAttrs (where it
+	// names the element type of every attribute in the block).
+	Type string
+
+	// Required marks whether an attribute or singular block must be
+	// present.
+	Required bool
+
+	// Object holds the field specs of a KindObject node, keyed by the Go
+	// side's struct/codec name.
+	Object map[string]*Spec
+
+	// Nested holds the object spec for the body of a KindBlock or
+	// KindBlockList node.
+	Nested *Spec
+}
+
+// NewObject describes the top-level (or nested) set of fields that make up
+// a TaskConfig block. fields is keyed by the name used to decode into the
+// destination struct.
+func NewObject(fields map[string]*Spec) *Spec {
+	return &Spec{Kind: KindObject, Object: fields}
+}
+
+// NewAttr describes a plain `name = <value>` attribute of the given cty
+// type expression.
+func NewAttr(name, typeExpr string, required bool) *Spec {
+	return &Spec{Kind: KindAttr, Name: name, Type: typeExpr, Required: required}
+}
+
+// NewBlock describes a `name { ... }` block that may appear at most once.
+func NewBlock(name string, required bool, nested *Spec) *Spec {
+	return &Spec{Kind: KindBlock, Name: name, Required: required, Nested: nested}
+}
+
+// NewBlockList describes a `name { ... }` block that may be repeated zero
+// or more times, decoding to a list.
+func NewBlockList(name string, nested *Spec) *Spec {
+	return &Spec{Kind: KindBlockList, Name: name, Nested: nested}
+}
+
+// NewBlockAttrs describes a `name { k = v ... }` block whose body is an
+// arbitrary set of attributes, all sharing typeExpr, decoding to a
+// map[string]<typeExpr>.
+func NewBlockAttrs(name, typeExpr string, required bool) *Spec {
+	return &Spec{Kind: KindBlockAttrs, Name: name, Type: typeExpr, Required: required}
+}